@@ -0,0 +1,122 @@
+// Package client provides a typed wrapper around Wrangler's inter-plugin RPC surface, so
+// sibling plugins can move, merge, and copy threads without hand-rolling p.API.PluginHTTP
+// calls and JSON payloads themselves.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+)
+
+const pluginID = "com.mattermost.plugin-wrangler"
+
+// Client calls Wrangler's inter-plugin RPC endpoints via the host plugin API's PluginHTTP,
+// which routes the request to Wrangler in-process without an HTTP round trip.
+type Client struct {
+	api plugin.API
+}
+
+// New builds a Client that calls Wrangler through api.PluginHTTP.
+func New(api plugin.API) *Client {
+	return &Client{api: api}
+}
+
+// ThreadRequest describes a thread to act on and the options to act on it with.
+type ThreadRequest struct {
+	UserID          string
+	RootPostID      string
+	TargetChannelID string
+	TargetPostID    string
+	ShowMessage     bool
+	UpdateLinks     bool
+}
+
+// MoveThread moves the thread rooted at req.RootPostID into req.TargetChannelID.
+func (c *Client) MoveThread(req ThreadRequest) (*model.Post, error) {
+	return c.call("move-thread", req)
+}
+
+// MergeThread merges the thread rooted at req.RootPostID into the thread rooted at
+// req.TargetPostID.
+func (c *Client) MergeThread(req ThreadRequest) (*model.Post, error) {
+	return c.call("merge-thread", req)
+}
+
+// CopyThread copies the thread rooted at req.RootPostID into req.TargetChannelID.
+func (c *Client) CopyThread(req ThreadRequest) (*model.Post, error) {
+	return c.call("copy-thread", req)
+}
+
+func (c *Client) call(endpoint string, req ThreadRequest) (*model.Post, error) {
+	body, err := json.Marshal(rpcThreadRequest{
+		UserID:          req.UserID,
+		RootPostID:      req.RootPostID,
+		TargetChannelID: req.TargetChannelID,
+		TargetPostID:    req.TargetPostID,
+		ShowMessage:     req.ShowMessage,
+		UpdateLinks:     req.UpdateLinks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// PluginHTTP routes on the first path segment, which names the target plugin; the
+	// remainder is what Wrangler's own router sees.
+	url := fmt.Sprintf("/%s/plugins/v1/%s", pluginID, endpoint)
+
+	httpRequest, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	resp := c.api.PluginHTTP(httpRequest)
+	if resp == nil {
+		return nil, fmt.Errorf("received no response from the %s plugin; is it installed and enabled?", pluginID)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr errorResponse
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("wrangler returned an error: %s", apiErr.Message)
+		}
+		return nil, fmt.Errorf("wrangler returned status %d", resp.StatusCode)
+	}
+
+	var post model.Post
+	if err := json.Unmarshal(respBody, &post); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &post, nil
+}
+
+// rpcThreadRequest mirrors the JSON payload shape Wrangler's server expects; kept in sync
+// with server/plugin_rpc.go by hand since the two can't share a package without this client
+// depending on Wrangler's main package.
+type rpcThreadRequest struct {
+	UserID          string `json:"user_id"`
+	RootPostID      string `json:"root_post_id"`
+	TargetChannelID string `json:"target_channel_id"`
+	TargetPostID    string `json:"target_post_id"`
+	ShowMessage     bool   `json:"show_message"`
+	UpdateLinks     bool   `json:"update_links"`
+}
+
+// errorResponse mirrors server.apiErrorResponse.
+type errorResponse struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-wrangler/server/telemetry"
+)
+
+const (
+	headerMattermostUserID = "Mattermost-User-Id"
+)
+
+// apiErrorResponse is the JSON body returned for any non-2xx response from the API.
+type apiErrorResponse struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code"`
+}
+
+// initializeAPI builds the router used to serve the plugin's REST API, wiring up the
+// recovery and auth middleware ahead of the versioned route handlers.
+func (p *Plugin) initializeAPI() *mux.Router {
+	router := mux.NewRouter()
+	router.Use(p.withRecovery)
+
+	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter.Use(p.checkAuth)
+
+	apiRouter.HandleFunc("/threads/move", p.httpMoveThread).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/threads/merge", p.httpMergeThread).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/threads/attach", p.httpAttachThread).Methods(http.MethodPost)
+	apiRouter.HandleFunc("/threads/copy", p.httpCopyThread).Methods(http.MethodPost)
+
+	p.initializePluginRoutes(router)
+
+	return router
+}
+
+// ServeHTTP demultiplexes HTTP requests routed to the plugin from the Mattermost server.
+func (p *Plugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Request) {
+	p.router.ServeHTTP(w, r)
+}
+
+// withRecovery recovers from panics in API handlers, logging the URL and stack so a bad
+// request can't take down the whole plugin.
+func (p *Plugin) withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if x := recover(); x != nil {
+				p.client.Log.Warn("recovered from a panic",
+					"url", r.URL.String(),
+					"error", x,
+					"stack", string(debug.Stack()))
+				writeAPIError(w, http.StatusInternalServerError, "internal server error")
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkAuth requires a valid Mattermost-User-Id header and enforces the configured
+// allow-list before letting a request reach a handler.
+func (p *Plugin) checkAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Header.Get(headerMattermostUserID)
+		if userID == "" {
+			writeAPIError(w, http.StatusUnauthorized, "not authorized")
+			return
+		}
+
+		if !p.getConfiguration().userIsAllowed(userID) {
+			writeAPIError(w, http.StatusForbidden, "user is not allowed to use Wrangler")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeAPIError writes a JSON-encoded apiErrorResponse with the given status code.
+func writeAPIError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_ = json.NewEncoder(w).Encode(apiErrorResponse{
+		Message:    message,
+		StatusCode: statusCode,
+	})
+}
+
+// threadRequest is the common JSON payload shape for the thread-manipulation endpoints.
+type threadRequest struct {
+	RootPostID      string `json:"root_post_id"`
+	TargetChannelID string `json:"target_channel_id"`
+	TargetPostID    string `json:"target_post_id"`
+	ShowMessage     bool   `json:"show_message"`
+	UpdateLinks     bool   `json:"update_links"`
+}
+
+func (p *Plugin) httpMoveThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get(headerMattermostUserID)
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	newRoot, err := p.moveThread(userID, req.RootPostID, req.TargetChannelID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourceAPI,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(userID, "move", telemetry.SourceAPI)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+func (p *Plugin) httpMergeThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get(headerMattermostUserID)
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if !p.getConfiguration().MergeThreadEnable {
+		writeAPIError(w, http.StatusForbidden, "merge is disabled")
+		return
+	}
+
+	newRoot, err := p.mergeThread(userID, req.RootPostID, req.TargetPostID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourceAPI,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(userID, "merge", telemetry.SourceAPI)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+func (p *Plugin) httpAttachThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get(headerMattermostUserID)
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	newRoot, err := p.attachThread(userID, req.RootPostID, req.TargetPostID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourceAPI,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(userID, "attach", telemetry.SourceAPI)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+func (p *Plugin) httpCopyThread(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get(headerMattermostUserID)
+
+	var req threadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	newRoot, err := p.copyThread(userID, req.RootPostID, req.TargetChannelID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourceAPI,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(userID, "copy", telemetry.SourceAPI)
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+// writeAPIJSON writes v as a JSON response body with a 200 status code.
+func writeAPIJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+
+	"github.com/mattermost/mattermost-plugin-wrangler/server/telemetry"
+)
+
+const commandTrigger = "wrangler"
+
+// getCommand builds the `/wrangler` slash command registration, toggling the merge
+// subcommand and autocomplete hints according to the plugin configuration.
+func getCommand(autoCompleteEnabled, mergeEnabled bool) *model.Command {
+	cmd := &model.Command{
+		Trigger:          commandTrigger,
+		AutoComplete:     autoCompleteEnabled,
+		AutoCompleteDesc: "Move, merge, attach, or copy threads.",
+		AutoCompleteHint: "[command]",
+		DisplayName:      "Wrangler",
+		Description:      "Corral your threads.",
+	}
+
+	if !autoCompleteEnabled {
+		return cmd
+	}
+
+	autocompleteData := model.NewAutocompleteData(commandTrigger, "[command]", "Available commands: move, attach, copy")
+	autocompleteData.AddCommand(model.NewAutocompleteData("move", "[channel]", "Move a thread to the specified channel"))
+	autocompleteData.AddCommand(model.NewAutocompleteData("attach", "[postID]", "Attach a single message to the thread rooted at postID, or to the default attach channel if omitted"))
+	autocompleteData.AddCommand(model.NewAutocompleteData("copy", "[channel]", "Copy a thread to the specified channel"))
+	if mergeEnabled {
+		autocompleteData.AddCommand(model.NewAutocompleteData("merge", "[postID]", "Merge a thread into the thread rooted at postID"))
+	}
+
+	cmd.AutocompleteData = autocompleteData
+
+	return cmd
+}
+
+// ExecuteCommand dispatches a `/wrangler` invocation to the appropriate thread operation,
+// reusing the same logic the HTTP API exposes in api.go.
+func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
+	config := p.getConfiguration()
+	if !config.userIsAllowed(args.UserId) {
+		return p.commandError("You are not allowed to use Wrangler.")
+	}
+
+	fields := strings.Fields(args.Command)
+	if len(fields) < 2 {
+		return p.commandError("A subcommand is required: move, merge, attach, or copy.")
+	}
+
+	subCommand := fields[1]
+	rest := fields[2:]
+
+	switch subCommand {
+	case "move":
+		return p.executeMove(args, rest)
+	case "merge":
+		if !config.MergeThreadEnable {
+			return p.commandError("The merge subcommand is disabled.")
+		}
+		return p.executeMerge(args, rest)
+	case "attach":
+		return p.executeAttach(args, rest)
+	case "copy":
+		return p.executeCopy(args, rest)
+	default:
+		return p.commandError(fmt.Sprintf("Unknown subcommand %q.", subCommand))
+	}
+}
+
+func (p *Plugin) executeMove(args *model.CommandArgs, rest []string) (*model.CommandResponse, *model.AppError) {
+	if len(rest) < 1 {
+		return p.commandError("Usage: /wrangler move [target-channel-id] [post-id]")
+	}
+
+	rootPostID := args.RootId
+	if len(rest) > 1 {
+		rootPostID = rest[1]
+	}
+
+	if _, err := p.moveThread(args.UserId, rootPostID, rest[0], ThreadOptions{ShowMessage: true, Source: telemetry.SourceCommand}); err != nil {
+		p.client.Log.Warn("failed to move thread", "error", err.Error())
+		p.tracker.TrackCommandError(args.UserId, "move", telemetry.SourceCommand)
+		return p.commandError(fmt.Sprintf("Failed to move thread: %s", err.Error()))
+	}
+
+	return &model.CommandResponse{}, nil
+}
+
+func (p *Plugin) executeMerge(args *model.CommandArgs, rest []string) (*model.CommandResponse, *model.AppError) {
+	if len(rest) < 1 {
+		return p.commandError("Usage: /wrangler merge [target-post-id]")
+	}
+
+	if _, err := p.mergeThread(args.UserId, args.RootId, rest[0], ThreadOptions{ShowMessage: true, Source: telemetry.SourceCommand}); err != nil {
+		p.client.Log.Warn("failed to merge thread", "error", err.Error())
+		p.tracker.TrackCommandError(args.UserId, "merge", telemetry.SourceCommand)
+		return p.commandError(fmt.Sprintf("Failed to merge thread: %s", err.Error()))
+	}
+
+	return &model.CommandResponse{}, nil
+}
+
+func (p *Plugin) executeAttach(args *model.CommandArgs, rest []string) (*model.CommandResponse, *model.AppError) {
+	var targetRootID string
+	if len(rest) > 0 {
+		targetRootID = rest[0]
+	}
+
+	if _, err := p.attachThread(args.UserId, args.RootId, targetRootID, ThreadOptions{Source: telemetry.SourceCommand}); err != nil {
+		p.client.Log.Warn("failed to attach post", "error", err.Error())
+		p.tracker.TrackCommandError(args.UserId, "attach", telemetry.SourceCommand)
+		return p.commandError(fmt.Sprintf("Failed to attach post: %s", err.Error()))
+	}
+
+	return &model.CommandResponse{}, nil
+}
+
+func (p *Plugin) executeCopy(args *model.CommandArgs, rest []string) (*model.CommandResponse, *model.AppError) {
+	if len(rest) < 1 {
+		return p.commandError("Usage: /wrangler copy [target-channel-id] [post-id]")
+	}
+
+	rootPostID := args.RootId
+	if len(rest) > 1 {
+		rootPostID = rest[1]
+	}
+
+	if _, err := p.copyThread(args.UserId, rootPostID, rest[0], ThreadOptions{ShowMessage: true, Source: telemetry.SourceCommand}); err != nil {
+		p.client.Log.Warn("failed to copy thread", "error", err.Error())
+		p.tracker.TrackCommandError(args.UserId, "copy", telemetry.SourceCommand)
+		return p.commandError(fmt.Sprintf("Failed to copy thread: %s", err.Error()))
+	}
+
+	return &model.CommandResponse{}, nil
+}
+
+func (p *Plugin) commandError(message string) (*model.CommandResponse, *model.AppError) {
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         message,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestGetCommand(t *testing.T) {
+	t.Run("autocomplete disabled skips autocomplete data", func(t *testing.T) {
+		cmd := getCommand(false, false)
+		assert.False(t, cmd.AutoComplete)
+		assert.Nil(t, cmd.AutocompleteData)
+	})
+
+	t.Run("merge subcommand only listed when enabled", func(t *testing.T) {
+		withMerge := getCommand(true, true)
+		withoutMerge := getCommand(true, false)
+
+		assert.Len(t, withMerge.AutocompleteData.SubCommands, 4)
+		assert.Len(t, withoutMerge.AutocompleteData.SubCommands, 3)
+	})
+}
+
+func TestExecuteCommandDispatch(t *testing.T) {
+	t.Run("rejects a user not on the allow-list before touching the client", func(t *testing.T) {
+		p := &Plugin{configuration: &configuration{AllowedUserIDs: "someone-else"}}
+
+		resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{UserId: "user1", Command: "/wrangler move channel1"})
+		assert.Nil(t, appErr)
+		assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+		assert.Contains(t, resp.Text, "not allowed")
+	})
+
+	t.Run("requires a subcommand", func(t *testing.T) {
+		p := &Plugin{configuration: &configuration{}}
+
+		resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{UserId: "user1", Command: "/wrangler"})
+		assert.Nil(t, appErr)
+		assert.Contains(t, resp.Text, "subcommand is required")
+	})
+
+	t.Run("rejects an unknown subcommand", func(t *testing.T) {
+		p := &Plugin{configuration: &configuration{}}
+
+		resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{UserId: "user1", Command: "/wrangler frobnicate"})
+		assert.Nil(t, appErr)
+		assert.Contains(t, resp.Text, "Unknown subcommand")
+	})
+
+	t.Run("rejects merge when the subcommand is disabled", func(t *testing.T) {
+		p := &Plugin{configuration: &configuration{MergeThreadEnable: false}}
+
+		resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{UserId: "user1", Command: "/wrangler merge post1"})
+		assert.Nil(t, appErr)
+		assert.Contains(t, resp.Text, "merge subcommand is disabled")
+	})
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// configuration captures the plugin's external configuration as exposed in the Mattermost
+// server configuration, as well as values computed from the configuration. Any public
+// fields will be deserialized from the Mattermost server configuration in OnConfigurationChange.
+//
+// As plugins are inherently concurrent (hooks being called asynchronously), and the plugin
+// configuration can change at any time, access to the configuration must be synchronized. The
+// strategy used in this plugin is to guard a pointer to the configuration, and clone the entire
+// struct whenever it changes. You may replace this with whatever strategy you see fit.
+type configuration struct {
+	// CommandAutoCompleteEnable toggles the slash command's autocomplete entries.
+	CommandAutoCompleteEnable bool
+
+	// MergeThreadEnable toggles the `/wrangler merge` subcommand.
+	MergeThreadEnable bool
+
+	// AllowedUserIDs, if set, restricts which users may move, merge, attach, or copy
+	// threads, whether via the slash command or the HTTP API.
+	AllowedUserIDs string
+
+	// AllowedTeamNames, if set, restricts which teams threads may be moved or copied into,
+	// by name. An empty value allows any team.
+	AllowedTeamNames string
+
+	// DefaultAttachChannelName, if set, names the "team-name/channel-name" that a quick
+	// attach (one with no explicit target thread) lands in.
+	DefaultAttachChannelName string
+
+	// MaxRecommendedThreadSize, if set above zero, is the post count above which a
+	// move/merge/copy is considered to have exceeded the recommended size for the
+	// operation. It is informational only (tracked via telemetry) and never blocks an
+	// operation; a value of zero means no limit is configured.
+	MaxRecommendedThreadSize int
+
+	// allowedTeamIDs is the resolved form of AllowedTeamNames, populated by
+	// resolveConfiguration. It must not be set directly.
+	allowedTeamIDs []string
+
+	// defaultAttachChannelID is the resolved form of DefaultAttachChannelName, populated by
+	// resolveConfiguration. It must not be set directly.
+	defaultAttachChannelID string
+}
+
+// Clone copies the configuration, giving the clone its own copy of the allowedTeamIDs slice.
+func (c *configuration) Clone() *configuration {
+	clone := *c
+	clone.allowedTeamIDs = append([]string(nil), c.allowedTeamIDs...)
+	return &clone
+}
+
+// IsValid checks if all needed fields are set.
+func (c *configuration) IsValid() error {
+	return nil
+}
+
+// getConfiguration retrieves the active configuration under lock, making it safe to use
+// concurrently. The active configuration may change underneath the client of this method, but
+// the struct returned by this API call is considered immutable.
+func (p *Plugin) getConfiguration() *configuration {
+	p.configurationLock.RLock()
+	defer p.configurationLock.RUnlock()
+
+	if p.configuration == nil {
+		return &configuration{}
+	}
+
+	return p.configuration
+}
+
+// setConfiguration replaces the active configuration under lock.
+//
+// Do not call setConfiguration while holding the configurationLock, as sync.Mutex is not
+// reentrant. In particular, avoid using one of the methods on configuration itself as the
+// argument to this method, as that triggers a data race.
+func (p *Plugin) setConfiguration(configuration *configuration) {
+	p.configurationLock.Lock()
+	defer p.configurationLock.Unlock()
+
+	if configuration != nil && p.configuration == configuration {
+		if reflect.ValueOf(configuration).Elem().NumField() == 0 {
+			return
+		}
+
+		panic("setConfiguration called with the existing configuration")
+	}
+
+	p.configuration = configuration
+}
+
+// OnConfigurationChange is invoked when configuration changes may have been made.
+func (p *Plugin) OnConfigurationChange() error {
+	var configuration = new(configuration)
+
+	if err := p.API.LoadPluginConfiguration(configuration); err != nil {
+		return errors.Wrap(err, "failed to load plugin configuration")
+	}
+
+	// p.client is nil until OnActivate runs; OnConfigurationChange can fire before then
+	// during plugin startup, before there's a client to resolve names with.
+	if p.client != nil {
+		p.resolveConfiguration(configuration)
+		p.reloadTracker()
+	}
+
+	p.setConfiguration(configuration)
+
+	return nil
+}
+
+// resolveConfiguration turns the human-friendly, name-based settings an admin enters into
+// the System Console into the IDs the rest of the plugin operates on. A lookup failure is
+// logged rather than failing activation, so a typo in one setting doesn't take down the
+// whole plugin: a team name that fails to resolve is left out of allowedTeamIDs (if none
+// resolve, the list is empty and every team is allowed), and a default attach channel that
+// fails to resolve leaves defaultAttachChannelID empty.
+func (p *Plugin) resolveConfiguration(configuration *configuration) {
+	configuration.allowedTeamIDs = nil
+	for _, teamName := range strings.Split(configuration.AllowedTeamNames, ",") {
+		teamName = strings.TrimSpace(teamName)
+		if teamName == "" {
+			continue
+		}
+
+		team, err := p.client.Team.GetByName(teamName)
+		if err != nil {
+			p.client.Log.Warn("failed to resolve allowed team name", "team_name", teamName, "error", err.Error())
+			continue
+		}
+
+		configuration.allowedTeamIDs = append(configuration.allowedTeamIDs, team.Id)
+	}
+
+	configuration.defaultAttachChannelID = ""
+	teamName, channelName, ok := splitTeamAndChannelName(configuration.DefaultAttachChannelName)
+	if !ok {
+		if configuration.DefaultAttachChannelName != "" {
+			p.client.Log.Warn("default attach channel must be in the form team-name/channel-name", "configured_value", configuration.DefaultAttachChannelName)
+		}
+		return
+	}
+
+	team, err := p.client.Team.GetByName(teamName)
+	if err != nil {
+		p.client.Log.Warn("failed to resolve default attach channel's team", "team_name", teamName, "error", err.Error())
+		return
+	}
+
+	channel, err := p.client.Channel.GetByName(team.Id, channelName, false)
+	if err != nil {
+		p.client.Log.Warn("failed to resolve default attach channel", "team_name", teamName, "channel_name", channelName, "error", err.Error())
+		return
+	}
+
+	configuration.defaultAttachChannelID = channel.Id
+}
+
+// splitTeamAndChannelName splits a "team-name/channel-name" configuration value into its
+// two parts.
+func splitTeamAndChannelName(value string) (teamName, channelName string, ok bool) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// teamIsAllowed reports whether teamID is a permitted move/copy destination. An empty
+// allow-list permits every team.
+func (c *configuration) teamIsAllowed(teamID string) bool {
+	if len(c.allowedTeamIDs) == 0 {
+		return true
+	}
+
+	for _, allowed := range c.allowedTeamIDs {
+		if allowed == teamID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exceedsConfiguredLimit reports whether postCount exceeds MaxRecommendedThreadSize. A
+// MaxRecommendedThreadSize of zero means no limit is configured, so nothing can exceed it.
+func (c *configuration) exceedsConfiguredLimit(postCount int) bool {
+	return c.MaxRecommendedThreadSize > 0 && postCount > c.MaxRecommendedThreadSize
+}
+
+// userIsAllowed reports whether userID may invoke Wrangler operations, honoring the
+// AllowedUserIDs allow-list when one is configured.
+func (c *configuration) userIsAllowed(userID string) bool {
+	if c.AllowedUserIDs == "" {
+		return true
+	}
+
+	for _, allowed := range strings.Split(c.AllowedUserIDs, ",") {
+		if strings.TrimSpace(allowed) == userID {
+			return true
+		}
+	}
+
+	return false
+}
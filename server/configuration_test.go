@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+)
+
+func TestSplitTeamAndChannelName(t *testing.T) {
+	for name, tc := range map[string]struct {
+		value       string
+		wantTeam    string
+		wantChannel string
+		wantOK      bool
+	}{
+		"valid":            {"team-name/channel-name", "team-name", "channel-name", true},
+		"extra slash kept": {"team-name/channel-name/extra", "team-name", "channel-name/extra", true},
+		"no slash":         {"team-name", "", "", false},
+		"empty team":       {"/channel-name", "", "", false},
+		"empty channel":    {"team-name/", "", "", false},
+		"empty value":      {"", "", "", false},
+		"trims whitespace": {"  team-name/channel-name  ", "team-name", "channel-name", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			team, channel, ok := splitTeamAndChannelName(tc.value)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantTeam, team)
+			assert.Equal(t, tc.wantChannel, channel)
+		})
+	}
+}
+
+func TestConfigurationUserIsAllowed(t *testing.T) {
+	t.Run("empty allow-list allows everyone", func(t *testing.T) {
+		c := &configuration{}
+		assert.True(t, c.userIsAllowed("user1"))
+	})
+
+	t.Run("allows listed users", func(t *testing.T) {
+		c := &configuration{AllowedUserIDs: "user1, user2"}
+		assert.True(t, c.userIsAllowed("user1"))
+		assert.True(t, c.userIsAllowed("user2"))
+	})
+
+	t.Run("rejects users not on the list", func(t *testing.T) {
+		c := &configuration{AllowedUserIDs: "user1"}
+		assert.False(t, c.userIsAllowed("user2"))
+	})
+}
+
+func TestConfigurationTeamIsAllowed(t *testing.T) {
+	t.Run("empty allow-list allows every team", func(t *testing.T) {
+		c := &configuration{}
+		assert.True(t, c.teamIsAllowed("team1"))
+	})
+
+	t.Run("rejects teams not on the list", func(t *testing.T) {
+		c := &configuration{allowedTeamIDs: []string{"team1"}}
+		assert.True(t, c.teamIsAllowed("team1"))
+		assert.False(t, c.teamIsAllowed("team2"))
+	})
+}
+
+func TestConfigurationExceedsConfiguredLimit(t *testing.T) {
+	t.Run("no limit configured never exceeds", func(t *testing.T) {
+		c := &configuration{}
+		assert.False(t, c.exceedsConfiguredLimit(1000))
+	})
+
+	t.Run("at the limit does not exceed", func(t *testing.T) {
+		c := &configuration{MaxRecommendedThreadSize: 10}
+		assert.False(t, c.exceedsConfiguredLimit(10))
+	})
+
+	t.Run("over the limit exceeds", func(t *testing.T) {
+		c := &configuration{MaxRecommendedThreadSize: 10}
+		assert.True(t, c.exceedsConfiguredLimit(11))
+	})
+}
+
+func TestResolveConfiguration(t *testing.T) {
+	t.Run("resolves allowed team names and the default attach channel", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetTeamByName", "team-a").Return(&model.Team{Id: "team-a-id"}, nil)
+		api.On("GetTeamByName", "team-b").Return(&model.Team{Id: "team-b-id"}, nil)
+		api.On("GetChannelByName", "team-b-id", "town-square", false).Return(&model.Channel{Id: "channel-id"}, nil)
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+
+		cfg := &configuration{
+			AllowedTeamNames:         "team-a, team-b",
+			DefaultAttachChannelName: "team-b/town-square",
+		}
+		p.resolveConfiguration(cfg)
+
+		assert.Equal(t, []string{"team-a-id", "team-b-id"}, cfg.allowedTeamIDs)
+		assert.Equal(t, "channel-id", cfg.defaultAttachChannelID)
+	})
+
+	t.Run("logs and clears on lookup failure instead of failing", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetTeamByName", "missing-team").Return(nil, &model.AppError{Message: "not found"})
+		api.On("LogWarn", "failed to resolve allowed team name", "team_name", "missing-team", "error", mock.Anything).Return()
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+
+		cfg := &configuration{AllowedTeamNames: "missing-team"}
+		p.resolveConfiguration(cfg)
+
+		assert.Empty(t, cfg.allowedTeamIDs)
+		assert.Empty(t, cfg.defaultAttachChannelID)
+	})
+}
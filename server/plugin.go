@@ -3,11 +3,14 @@ package main
 import (
 	"sync"
 
+	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/mattermost/mattermost/server/public/plugin"
 	"github.com/mattermost/mattermost/server/public/pluginapi"
+
+	"github.com/mattermost/mattermost-plugin-wrangler/server/telemetry"
 )
 
 // Plugin implements the interface expected by the Mattermost server to communicate between the server and plugin processes.
@@ -18,6 +21,13 @@ type Plugin struct {
 
 	BotUserID string
 
+	// router dispatches HTTP requests routed to the plugin to the API handlers in api.go.
+	router *mux.Router
+
+	// tracker records anonymized usage events. It is reloaded whenever
+	// ServiceSettings.EnableDiagnostics changes. See telemetry.Tracker for usage.
+	tracker *telemetry.Tracker
+
 	// configurationLock synchronizes access to the configuration.
 	configurationLock sync.RWMutex
 
@@ -35,6 +45,15 @@ var BuildHashShort string
 // BuildDate is the build date of the build.
 var BuildDate string
 
+// rudderDataplaneURL and rudderWriteKey configure the telemetry client and are set via
+// -ldflags at build time, mirroring the other first-party plugins.
+var (
+	rudderDataplaneURL string
+	rudderWriteKey     string
+)
+
+const pluginID = "com.mattermost.plugin-wrangler"
+
 // OnActivate runs when the plugin activates and ensures the plugin is properly
 // configured.
 func (p *Plugin) OnActivate() error {
@@ -46,6 +65,13 @@ func (p *Plugin) OnActivate() error {
 
 	p.client = pluginapi.NewClient(p.API, p.Driver)
 
+	// OnConfigurationChange may have run before p.client existed to resolve names against,
+	// so do the resolution pass we skipped then now that it's available.
+	resolved := config.Clone()
+	p.resolveConfiguration(resolved)
+	p.setConfiguration(resolved)
+	config = resolved
+
 	bot := &model.Bot{
 		Username:    "wrangler",
 		DisplayName: "Wrangler",
@@ -66,5 +92,41 @@ func (p *Plugin) OnActivate() error {
 		return errors.Wrap(err, "failed to register wrangler command")
 	}
 
+	p.router = p.initializeAPI()
+
+	p.reloadTracker()
+
+	return nil
+}
+
+// reloadTracker (re)builds p.tracker to reflect the current ServiceSettings.EnableDiagnostics
+// setting, closing any previously running tracker first.
+func (p *Plugin) reloadTracker() {
+	if p.tracker != nil {
+		p.tracker.Close()
+		p.tracker = nil
+	}
+
+	enableDiagnostics := p.client.Configuration.GetConfig().ServiceSettings.EnableDiagnostics
+	if enableDiagnostics == nil || !*enableDiagnostics {
+		return
+	}
+
+	diagnosticID := p.API.GetDiagnosticId()
+
+	p.tracker = telemetry.NewTracker(
+		diagnosticID,
+		p.client.System.GetServerVersion(),
+		pluginID,
+		BuildHashShort,
+		rudderDataplaneURL,
+		rudderWriteKey,
+		true,
+	)
+}
+
+// OnDeactivate stops background work started by OnActivate.
+func (p *Plugin) OnDeactivate() error {
+	p.tracker.Close()
 	return nil
 }
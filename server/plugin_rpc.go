@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/mattermost/mattermost-plugin-wrangler/server/telemetry"
+)
+
+const headerMattermostPluginID = "Mattermost-Plugin-Id"
+
+// initializePluginRoutes registers the inter-plugin RPC surface used by sibling plugins that
+// call in through p.API.PluginHTTP rather than the user-facing REST API. These routes trust
+// Mattermost-Plugin-Id instead of Mattermost-User-Id, so they're kept on their own subrouter
+// with their own auth middleware rather than reusing /api/v1/threads/*.
+func (p *Plugin) initializePluginRoutes(router *mux.Router) {
+	pluginRouter := router.PathPrefix("/plugins/v1").Subrouter()
+	pluginRouter.Use(p.checkPluginAuth)
+
+	pluginRouter.HandleFunc("/move-thread", p.rpcMoveThread).Methods(http.MethodPost)
+	pluginRouter.HandleFunc("/merge-thread", p.rpcMergeThread).Methods(http.MethodPost)
+	pluginRouter.HandleFunc("/copy-thread", p.rpcCopyThread).Methods(http.MethodPost)
+}
+
+// checkPluginAuth requires the Mattermost-Plugin-Id header the server attaches to requests
+// made via p.API.PluginHTTP, so only other server-side plugins can reach this surface.
+func (p *Plugin) checkPluginAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerMattermostPluginID) == "" {
+			writeAPIError(w, http.StatusUnauthorized, "not authorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rpcThreadRequest is the JSON payload shape accepted by the inter-plugin RPC endpoints.
+// UserID identifies who the operation should be attributed to, since there's no
+// Mattermost-User-Id header on a plugin-to-plugin call.
+type rpcThreadRequest struct {
+	UserID          string `json:"user_id"`
+	RootPostID      string `json:"root_post_id"`
+	TargetChannelID string `json:"target_channel_id"`
+	TargetPostID    string `json:"target_post_id"`
+	ShowMessage     bool   `json:"show_message"`
+	UpdateLinks     bool   `json:"update_links"`
+}
+
+func (p *Plugin) rpcMoveThread(w http.ResponseWriter, r *http.Request) {
+	callerID := r.Header.Get(headerMattermostPluginID)
+
+	var req rpcThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if !p.getConfiguration().userIsAllowed(req.UserID) {
+		writeAPIError(w, http.StatusForbidden, "user is not allowed to use Wrangler")
+		return
+	}
+
+	newRoot, err := p.moveThread(req.UserID, req.RootPostID, req.TargetChannelID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourcePlugin,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(req.UserID, "move", telemetry.SourcePlugin)
+		p.client.Log.Warn("inter-plugin move-thread failed", "caller_plugin_id", callerID, "error", err.Error())
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+func (p *Plugin) rpcMergeThread(w http.ResponseWriter, r *http.Request) {
+	callerID := r.Header.Get(headerMattermostPluginID)
+
+	var req rpcThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if !p.getConfiguration().userIsAllowed(req.UserID) {
+		writeAPIError(w, http.StatusForbidden, "user is not allowed to use Wrangler")
+		return
+	}
+
+	if !p.getConfiguration().MergeThreadEnable {
+		writeAPIError(w, http.StatusForbidden, "merge is disabled")
+		return
+	}
+
+	newRoot, err := p.mergeThread(req.UserID, req.RootPostID, req.TargetPostID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourcePlugin,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(req.UserID, "merge", telemetry.SourcePlugin)
+		p.client.Log.Warn("inter-plugin merge-thread failed", "caller_plugin_id", callerID, "error", err.Error())
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
+
+func (p *Plugin) rpcCopyThread(w http.ResponseWriter, r *http.Request) {
+	callerID := r.Header.Get(headerMattermostPluginID)
+
+	var req rpcThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if !p.getConfiguration().userIsAllowed(req.UserID) {
+		writeAPIError(w, http.StatusForbidden, "user is not allowed to use Wrangler")
+		return
+	}
+
+	newRoot, err := p.copyThread(req.UserID, req.RootPostID, req.TargetChannelID, ThreadOptions{
+		ShowMessage: req.ShowMessage,
+		UpdateLinks: req.UpdateLinks,
+		Source:      telemetry.SourcePlugin,
+	})
+	if err != nil {
+		p.tracker.TrackCommandError(req.UserID, "copy", telemetry.SourcePlugin)
+		p.client.Log.Warn("inter-plugin copy-thread failed", "caller_plugin_id", callerID, "error", err.Error())
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeAPIJSON(w, newRoot)
+}
@@ -0,0 +1,108 @@
+// Package telemetry tracks anonymized usage of Wrangler's thread operations so the
+// maintainers can prioritize features based on which flows are actually used.
+package telemetry
+
+import (
+	pluginapi "github.com/mattermost/mattermost-plugin-api/experimental/telemetry"
+)
+
+// Source identifies which surface triggered a thread operation.
+type Source string
+
+const (
+	// SourceCommand indicates the operation was triggered by the `/wrangler` slash command.
+	SourceCommand Source = "command"
+
+	// SourceAPI indicates the operation was triggered by the HTTP REST API.
+	SourceAPI Source = "api"
+
+	// SourcePlugin indicates the operation was triggered by another plugin over the
+	// inter-plugin RPC surface.
+	SourcePlugin Source = "plugin"
+)
+
+// Tracker records anonymized events describing how Wrangler is being used. A nil *Tracker
+// is safe to call methods on; every method becomes a no-op so callers don't need to check
+// whether diagnostics are enabled before tracking an event.
+type Tracker struct {
+	client pluginapi.Client
+}
+
+// NewTracker builds a Tracker around a rudder-backed telemetry client, rooted at the given
+// diagnostic ID. pluginID and pluginVersion identify this plugin's events in the shared
+// telemetry stream.
+func NewTracker(diagnosticID, serverVersion, pluginID, pluginVersion, rudderDataplaneURL, rudderWriteKey string, enableDiagnostics bool) *Tracker {
+	client := pluginapi.NewTrackerConfig(
+		pluginapi.NewRudderClient(rudderDataplaneURL, rudderWriteKey),
+		diagnosticID,
+		serverVersion,
+		pluginID,
+		pluginVersion,
+		"wrangler",
+		enableDiagnostics,
+	)
+
+	return &Tracker{client: client}
+}
+
+// Close releases the underlying telemetry client's resources.
+func (t *Tracker) Close() {
+	if t == nil {
+		return
+	}
+	_ = t.client.Close()
+}
+
+// ThreadOperationProperties describes the anonymized properties recorded for a thread
+// operation event.
+type ThreadOperationProperties struct {
+	PostCount     int
+	CrossTeam     bool
+	Source        Source
+	ExceededLimit bool
+}
+
+func (p ThreadOperationProperties) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"post_count":     p.PostCount,
+		"cross_team":     p.CrossTeam,
+		"source":         string(p.Source),
+		"exceeded_limit": p.ExceededLimit,
+	}
+}
+
+// TrackThreadMove records a thread_move event.
+func (t *Tracker) TrackThreadMove(userID string, props ThreadOperationProperties) {
+	t.track(userID, "thread_move", props.toMap())
+}
+
+// TrackThreadCopy records a thread_copy event.
+func (t *Tracker) TrackThreadCopy(userID string, props ThreadOperationProperties) {
+	t.track(userID, "thread_copy", props.toMap())
+}
+
+// TrackThreadMerge records a thread_merge event.
+func (t *Tracker) TrackThreadMerge(userID string, props ThreadOperationProperties) {
+	t.track(userID, "thread_merge", props.toMap())
+}
+
+// TrackThreadAttach records a thread_attach event.
+func (t *Tracker) TrackThreadAttach(userID string, props ThreadOperationProperties) {
+	t.track(userID, "thread_attach", props.toMap())
+}
+
+// TrackCommandError records that a command or API call failed, along with which operation
+// and surface it failed on.
+func (t *Tracker) TrackCommandError(userID, operation string, source Source) {
+	t.track(userID, "command_error", map[string]interface{}{
+		"operation": operation,
+		"source":    string(source),
+	})
+}
+
+func (t *Tracker) track(userID, event string, properties map[string]interface{}) {
+	if t == nil {
+		return
+	}
+	_ = t.client.TrackEvent(event, properties)
+}
@@ -0,0 +1,18 @@
+package telemetry
+
+import (
+	"testing"
+)
+
+func TestNilTrackerIsSafe(t *testing.T) {
+	var tracker *Tracker
+
+	// None of these should panic: a nil *Tracker is what callers get when diagnostics are
+	// disabled, and every method needs to be a safe no-op in that case.
+	tracker.TrackThreadMove("user1", ThreadOperationProperties{})
+	tracker.TrackThreadMerge("user1", ThreadOperationProperties{})
+	tracker.TrackThreadAttach("user1", ThreadOperationProperties{})
+	tracker.TrackThreadCopy("user1", ThreadOperationProperties{})
+	tracker.TrackCommandError("user1", "move", SourceCommand)
+	tracker.Close()
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-wrangler/server/telemetry"
+)
+
+// ThreadOptions controls the optional behavior of the thread-manipulation operations below.
+// The same struct is used by both the slash command executors and the HTTP API handlers so
+// the two surfaces can't drift.
+type ThreadOptions struct {
+	// ShowMessage, if true, leaves a message behind in the source channel linking to the
+	// post's new location. attachThread ignores it and posts no such message.
+	ShowMessage bool
+
+	// UpdateLinks, if true, passes each recreated post's message through
+	// rewritePermalinks, which returns it unchanged, so permalinks are not rewritten.
+	UpdateLinks bool
+
+	// Source identifies whether this operation was triggered by the slash command, the
+	// HTTP API, or another plugin over the inter-plugin RPC surface, for telemetry purposes.
+	Source telemetry.Source
+}
+
+// moveThread moves the thread rooted at rootPostID into targetChannelID, recreating each
+// post in order and removing the originals.
+func (p *Plugin) moveThread(userID, rootPostID, targetChannelID string, opts ThreadOptions) (*model.Post, error) {
+	rootPost, err := p.client.Post.GetPost(rootPostID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get root post")
+	}
+	if rootPost.RootId != "" {
+		return nil, errors.New("post is not the root of a thread")
+	}
+
+	if err := p.checkCanReadChannel(userID, rootPost.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkTargetChannelAllowed(targetChannelID); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkCanPostToChannel(userID, targetChannelID); err != nil {
+		return nil, err
+	}
+
+	posts, err := p.getThreadPosts(rootPostID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRoot, err := p.recreatePosts(posts, targetChannelID, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to recreate thread in target channel")
+	}
+
+	if opts.ShowMessage {
+		if err := p.postLinkMessage(userID, rootPost.ChannelId, newRoot); err != nil {
+			p.client.Log.Warn("failed to post move notice", "error", err.Error())
+		}
+	}
+
+	if err := p.deletePosts(posts); err != nil {
+		return nil, errors.Wrap(err, "failed to remove original thread")
+	}
+
+	p.tracker.TrackThreadMove(userID, telemetry.ThreadOperationProperties{
+		PostCount:     len(posts),
+		CrossTeam:     p.channelsCrossTeam(rootPost.ChannelId, targetChannelID),
+		Source:        opts.Source,
+		ExceededLimit: p.getConfiguration().exceedsConfiguredLimit(len(posts)),
+	})
+
+	return newRoot, nil
+}
+
+// mergeThread appends the thread rooted at rootPostID onto the end of the thread rooted at
+// targetRootID, removing the originals.
+func (p *Plugin) mergeThread(userID, rootPostID, targetRootID string, opts ThreadOptions) (*model.Post, error) {
+	targetRoot, err := p.client.Post.GetPost(targetRootID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get target thread root")
+	}
+
+	if err := p.checkCanPostToChannel(userID, targetRoot.ChannelId); err != nil {
+		return nil, err
+	}
+
+	rootPost, err := p.client.Post.GetPost(rootPostID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get root post")
+	}
+
+	if err := p.checkCanReadChannel(userID, rootPost.ChannelId); err != nil {
+		return nil, err
+	}
+
+	posts, err := p.getThreadPosts(rootPostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.appendPosts(posts, targetRoot, opts); err != nil {
+		return nil, errors.Wrap(err, "failed to merge thread")
+	}
+
+	if opts.ShowMessage {
+		if err := p.postLinkMessage(userID, rootPost.ChannelId, targetRoot); err != nil {
+			p.client.Log.Warn("failed to post merge notice", "error", err.Error())
+		}
+	}
+
+	if err := p.deletePosts(posts); err != nil {
+		return nil, errors.Wrap(err, "failed to remove merged thread")
+	}
+
+	p.tracker.TrackThreadMerge(userID, telemetry.ThreadOperationProperties{
+		PostCount:     len(posts),
+		CrossTeam:     p.channelsCrossTeam(rootPost.ChannelId, targetRoot.ChannelId),
+		Source:        opts.Source,
+		ExceededLimit: p.getConfiguration().exceedsConfiguredLimit(len(posts)),
+	})
+
+	return targetRoot, nil
+}
+
+// attachThread copies the single post postID onto the end of the thread rooted at
+// targetRootID, leaving the original post in place. If targetRootID is empty, the post is
+// instead attached as a new thread root in the configured default attach channel.
+func (p *Plugin) attachThread(userID, postID, targetRootID string, opts ThreadOptions) (*model.Post, error) {
+	post, err := p.client.Post.GetPost(postID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get post")
+	}
+
+	if err := p.checkCanReadChannel(userID, post.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if targetRootID == "" {
+		defaultChannelID := p.getConfiguration().defaultAttachChannelID
+		if defaultChannelID == "" {
+			return nil, errors.New("no target thread given and no default attach channel is configured")
+		}
+
+		if err := p.checkCanPostToChannel(userID, defaultChannelID); err != nil {
+			return nil, err
+		}
+
+		newRoot, err := p.recreatePosts([]*model.Post{post}, defaultChannelID, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to attach post to default channel")
+		}
+
+		p.tracker.TrackThreadAttach(userID, telemetry.ThreadOperationProperties{
+			PostCount: 1,
+			CrossTeam: p.channelsCrossTeam(post.ChannelId, defaultChannelID),
+			Source:    opts.Source,
+		})
+
+		return newRoot, nil
+	}
+
+	targetRoot, err := p.client.Post.GetPost(targetRootID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get target thread root")
+	}
+
+	if err := p.checkCanPostToChannel(userID, targetRoot.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if err := p.appendPosts([]*model.Post{post}, targetRoot, opts); err != nil {
+		return nil, errors.Wrap(err, "failed to attach post")
+	}
+
+	p.tracker.TrackThreadAttach(userID, telemetry.ThreadOperationProperties{
+		PostCount: 1,
+		CrossTeam: p.channelsCrossTeam(post.ChannelId, targetRoot.ChannelId),
+		Source:    opts.Source,
+	})
+
+	return targetRoot, nil
+}
+
+// copyThread copies the thread rooted at rootPostID into targetChannelID, leaving the
+// original thread in place.
+func (p *Plugin) copyThread(userID, rootPostID, targetChannelID string, opts ThreadOptions) (*model.Post, error) {
+	rootPost, err := p.client.Post.GetPost(rootPostID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get root post")
+	}
+
+	if err := p.checkCanReadChannel(userID, rootPost.ChannelId); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkTargetChannelAllowed(targetChannelID); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkCanPostToChannel(userID, targetChannelID); err != nil {
+		return nil, err
+	}
+
+	posts, err := p.getThreadPosts(rootPostID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRoot, err := p.recreatePosts(posts, targetChannelID, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to copy thread to target channel")
+	}
+
+	if opts.ShowMessage {
+		if err := p.postLinkMessage(userID, rootPost.ChannelId, newRoot); err != nil {
+			p.client.Log.Warn("failed to post copy notice", "error", err.Error())
+		}
+	}
+
+	p.tracker.TrackThreadCopy(userID, telemetry.ThreadOperationProperties{
+		PostCount:     len(posts),
+		CrossTeam:     p.channelsCrossTeam(rootPost.ChannelId, targetChannelID),
+		Source:        opts.Source,
+		ExceededLimit: p.getConfiguration().exceedsConfiguredLimit(len(posts)),
+	})
+
+	return newRoot, nil
+}
+
+// getThreadPosts fetches every post in the thread rooted at rootPostID, oldest first.
+func (p *Plugin) getThreadPosts(rootPostID string) ([]*model.Post, error) {
+	postList, err := p.client.Post.GetPostThread(rootPostID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get thread")
+	}
+
+	posts := make([]*model.Post, 0, len(postList.Order))
+	for _, id := range postList.Order {
+		posts = append(posts, postList.Posts[id])
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreateAt < posts[j].CreateAt
+	})
+
+	return posts, nil
+}
+
+// recreatePosts recreates posts, in order, as a new thread in targetChannelID and returns the
+// new root post.
+func (p *Plugin) recreatePosts(posts []*model.Post, targetChannelID string, opts ThreadOptions) (*model.Post, error) {
+	var newRoot *model.Post
+
+	for _, original := range posts {
+		newPost := &model.Post{
+			UserId:    original.UserId,
+			ChannelId: targetChannelID,
+			Message:   original.Message,
+		}
+		if newRoot != nil {
+			newPost.RootId = newRoot.Id
+		}
+
+		if opts.UpdateLinks {
+			newPost.Message = rewritePermalinks(newPost.Message, targetChannelID)
+		}
+
+		if err := p.client.Post.CreatePost(newPost); err != nil {
+			return nil, errors.Wrapf(err, "failed to create post %s", original.Id)
+		}
+
+		if newRoot == nil {
+			newRoot = newPost
+		}
+	}
+
+	return newRoot, nil
+}
+
+// appendPosts recreates posts, in order, as replies to targetRoot.
+func (p *Plugin) appendPosts(posts []*model.Post, targetRoot *model.Post, opts ThreadOptions) error {
+	for _, original := range posts {
+		newPost := &model.Post{
+			UserId:    original.UserId,
+			ChannelId: targetRoot.ChannelId,
+			RootId:    targetRoot.Id,
+			Message:   original.Message,
+		}
+
+		if opts.UpdateLinks {
+			newPost.Message = rewritePermalinks(newPost.Message, targetRoot.ChannelId)
+		}
+
+		if err := p.client.Post.CreatePost(newPost); err != nil {
+			return errors.Wrapf(err, "failed to create post %s", original.Id)
+		}
+	}
+
+	return nil
+}
+
+// deletePosts removes the given posts from their original channel.
+func (p *Plugin) deletePosts(posts []*model.Post) error {
+	for _, post := range posts {
+		if err := p.client.Post.DeletePost(post.Id); err != nil {
+			return errors.Wrapf(err, "failed to delete post %s", post.Id)
+		}
+	}
+
+	return nil
+}
+
+// postLinkMessage leaves a note in sourceChannelID pointing at where a thread ended up.
+func (p *Plugin) postLinkMessage(userID, sourceChannelID string, newRoot *model.Post) error {
+	siteURL := p.client.Configuration.GetConfig().ServiceSettings.SiteURL
+	if siteURL == nil || *siteURL == "" {
+		return errors.New("cannot build a permalink: ServiceSettings.SiteURL is not configured")
+	}
+	permalink := fmt.Sprintf("%s/_redirect/pl/%s", trimTrailingSlash(*siteURL), newRoot.Id)
+
+	return p.client.Post.CreatePost(&model.Post{
+		UserId:    p.BotUserID,
+		ChannelId: sourceChannelID,
+		Message:   fmt.Sprintf("This thread was moved by @%s: %s", userID, permalink),
+	})
+}
+
+// trimTrailingSlash removes a single trailing slash from s, if present.
+func trimTrailingSlash(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '/' {
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// checkCanReadChannel returns an error unless userID has permission to read channelID. This
+// keeps a user from moving, merging, attaching, or copying a post out of a channel they
+// don't otherwise have access to, even if they know or can guess its post ID.
+func (p *Plugin) checkCanReadChannel(userID, channelID string) error {
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionReadChannel) {
+		return errors.New("user does not have permission to read the source channel")
+	}
+
+	return nil
+}
+
+// checkCanPostToChannel returns an error unless userID has permission to post in channelID.
+func (p *Plugin) checkCanPostToChannel(userID, channelID string) error {
+	if !p.API.HasPermissionToChannel(userID, channelID, model.PermissionCreatePost) {
+		return errors.New("user does not have permission to post in the target channel")
+	}
+
+	return nil
+}
+
+// checkTargetChannelAllowed returns an error if targetChannelID's team isn't permitted by the
+// configured AllowedTeamNames allow-list.
+func (p *Plugin) checkTargetChannelAllowed(targetChannelID string) error {
+	config := p.getConfiguration()
+	if len(config.allowedTeamIDs) == 0 {
+		return nil
+	}
+
+	channel, err := p.client.Channel.Get(targetChannelID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get target channel")
+	}
+
+	if !config.teamIsAllowed(channel.TeamId) {
+		return errors.New("target channel's team is not an allowed move/copy destination")
+	}
+
+	return nil
+}
+
+// channelsCrossTeam reports whether channelAID and channelBID belong to different teams.
+// Lookup failures are treated as same-team so a telemetry hiccup can't fail an operation.
+func (p *Plugin) channelsCrossTeam(channelAID, channelBID string) bool {
+	if channelAID == channelBID {
+		return false
+	}
+
+	channelA, err := p.client.Channel.Get(channelAID)
+	if err != nil {
+		return false
+	}
+
+	channelB, err := p.client.Channel.Get(channelBID)
+	if err != nil {
+		return false
+	}
+
+	return channelA.TeamId != channelB.TeamId
+}
+
+// rewritePermalinks is a placeholder for permalink rewriting; full link resolution depends
+// on knowing the team name for targetChannelID, which callers can layer on as needed.
+func rewritePermalinks(message, targetChannelID string) string {
+	return message
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin/plugintest"
+	"github.com/mattermost/mattermost/server/public/pluginapi"
+)
+
+func TestChannelsCrossTeam(t *testing.T) {
+	t.Run("same channel is never cross-team", func(t *testing.T) {
+		p := &Plugin{}
+		assert.False(t, p.channelsCrossTeam("channel1", "channel1"))
+	})
+
+	t.Run("different teams", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", TeamId: "team1"}, nil)
+		api.On("GetChannel", "channel2").Return(&model.Channel{Id: "channel2", TeamId: "team2"}, nil)
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+		assert.True(t, p.channelsCrossTeam("channel1", "channel2"))
+	})
+
+	t.Run("same team", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", TeamId: "team1"}, nil)
+		api.On("GetChannel", "channel2").Return(&model.Channel{Id: "channel2", TeamId: "team1"}, nil)
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+		assert.False(t, p.channelsCrossTeam("channel1", "channel2"))
+	})
+
+	t.Run("lookup failure is treated as same-team", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetChannel", "channel1").Return(nil, &model.AppError{Message: "not found"})
+
+		p := &Plugin{client: pluginapi.NewClient(api, nil)}
+		assert.False(t, p.channelsCrossTeam("channel1", "channel2"))
+	})
+}
+
+func TestCheckCanReadChannel(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("HasPermissionToChannel", "user1", "channel1", model.PermissionReadChannel).Return(true)
+	api.On("HasPermissionToChannel", "user2", "channel1", model.PermissionReadChannel).Return(false)
+
+	p := &Plugin{client: pluginapi.NewClient(api, nil)}
+	p.API = api
+
+	assert.NoError(t, p.checkCanReadChannel("user1", "channel1"))
+	assert.Error(t, p.checkCanReadChannel("user2", "channel1"))
+}
+
+func TestCheckCanPostToChannel(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("HasPermissionToChannel", "user1", "channel1", model.PermissionCreatePost).Return(true)
+	api.On("HasPermissionToChannel", "user2", "channel1", model.PermissionCreatePost).Return(false)
+
+	p := &Plugin{client: pluginapi.NewClient(api, nil)}
+	p.API = api
+
+	assert.NoError(t, p.checkCanPostToChannel("user1", "channel1"))
+	assert.Error(t, p.checkCanPostToChannel("user2", "channel1"))
+}
+
+func TestCheckTargetChannelAllowed(t *testing.T) {
+	t.Run("no allow-list configured permits any channel", func(t *testing.T) {
+		p := &Plugin{configuration: &configuration{}}
+		assert.NoError(t, p.checkTargetChannelAllowed("channel1"))
+	})
+
+	t.Run("rejects a channel whose team is not allowed", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", TeamId: "other-team"}, nil)
+
+		p := &Plugin{
+			client:        pluginapi.NewClient(api, nil),
+			configuration: &configuration{allowedTeamIDs: []string{"allowed-team"}},
+		}
+		assert.Error(t, p.checkTargetChannelAllowed("channel1"))
+	})
+
+	t.Run("permits a channel whose team is allowed", func(t *testing.T) {
+		api := &plugintest.API{}
+		defer api.AssertExpectations(t)
+
+		api.On("GetChannel", "channel1").Return(&model.Channel{Id: "channel1", TeamId: "allowed-team"}, nil)
+
+		p := &Plugin{
+			client:        pluginapi.NewClient(api, nil),
+			configuration: &configuration{allowedTeamIDs: []string{"allowed-team"}},
+		}
+		assert.NoError(t, p.checkTargetChannelAllowed("channel1"))
+	})
+}
+
+func TestPostLinkMessageRequiresSiteURL(t *testing.T) {
+	api := &plugintest.API{}
+	defer api.AssertExpectations(t)
+
+	api.On("GetConfig").Return(&model.Config{})
+
+	p := &Plugin{client: pluginapi.NewClient(api, nil)}
+
+	err := p.postLinkMessage("user1", "channel1", &model.Post{Id: "post1"})
+	assert.Error(t, err)
+}